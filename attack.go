@@ -0,0 +1,71 @@
+package hazana
+
+import (
+	"fmt"
+	"time"
+)
+
+// Attack is the interface a user implements to describe what a single attacker does.
+// Implementations are cloned once per spawned attacker so Setup/Teardown can hold
+// per-attacker state such as a http.Client or a database connection.
+type Attack interface {
+	// Setup should prepare the attacker to be able to run Do.
+	Setup(c Config) error
+
+	// Do performs one request and reports its result.
+	// Do is called repeatedly so it must be safe to call many times.
+	Do() Result
+
+	// Teardown can be used to close any resources that were opened in Setup.
+	Teardown() error
+
+	// Clone should return a fresh copy of the Attack, ready to be setup.
+	Clone() Attack
+}
+
+// Result holds the outcome of a single Attack.Do call.
+type Result struct {
+	// RequestLabel identifies the kind of request this result belongs to,
+	// e.g. "GET /users" ; used to group Metrics.
+	RequestLabel string
+	StatusCode   int
+	Error        error
+}
+
+// result wraps a Result with the time it took to produce it.
+type result struct {
+	doResult Result
+	elapsed  time.Duration
+}
+
+// attack repeatedly calls a.Do() each time next fires, sending its result on results,
+// until quit fires.
+func attack(a Attack, next, quit chan bool, results chan result, timeout time.Duration) {
+	for {
+		select {
+		case <-next:
+			began := time.Now()
+			doResult := doWithTimeout(a, timeout)
+			results <- result{doResult: doResult, elapsed: time.Now().Sub(began)}
+		case <-quit:
+			return
+		}
+	}
+}
+
+// doWithTimeout calls a.Do() and returns its Result, unless it doesn't
+// return within timeout, in which case it returns a Result carrying a
+// timeout error instead. The a.Do() goroutine is left running; its Result is
+// discarded on the buffered channel once it eventually returns.
+func doWithTimeout(a Attack, timeout time.Duration) Result {
+	done := make(chan Result, 1)
+	go func() {
+		done <- a.Do()
+	}()
+	select {
+	case r := <-done:
+		return r
+	case <-time.After(timeout):
+		return Result{Error: fmt.Errorf("attack.Do did not return within %v", timeout)}
+	}
+}
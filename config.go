@@ -0,0 +1,90 @@
+package hazana
+
+import (
+	"flag"
+	"time"
+)
+
+var oSample = flag.Int("sample", 0, "number of calls to test the Attack with instead of running the full load test")
+
+// Config holds all the settings that control one load test run.
+type Config struct {
+	// RPS is the target number of requests per second during the full attack.
+	RPS int
+
+	// AttackTimeSec is the total duration of the test, rampup included, in seconds.
+	// When Stages is set, the full attack after rampup instead lasts the sum
+	// of the Stages' DurationSec.
+	AttackTimeSec int
+
+	// RampupTimeSec is how long, at the start of the test, it takes to grow
+	// from zero to RPS attackers.
+	RampupTimeSec int
+
+	// RampupStrategy chooses how attackers are spawned during rampup.
+	// Valid values are "linear" (default) and "exp2".
+	RampupStrategy string
+
+	// DoTimeoutSec bounds how long a single Attack.Do call may take.
+	DoTimeoutSec int
+
+	// Stages, when non-empty, replaces the single constant RPS of the full
+	// attack with a profile of segments, e.g. for spike or step-load tests.
+	// The RPS up to the first Stage is still RPS.
+	Stages []Stage
+
+	// Exporters receive live updates while the attack is running, so a long
+	// soak test isn't opaque until it finishes.
+	Exporters []Exporter
+
+	// SLO, when set, is evaluated against the live Metrics and can abort or
+	// throttle the attack when it is breached.
+	SLO *SLO
+
+	// OnSLOEvent, when set, is called with every SLOEvent so the attack's
+	// breach/recovery history can feed into alerting.
+	OnSLOEvent func(SLOEvent)
+
+	// Logger receives the runner's progress events. When nil, a Logger
+	// wrapping the stdlib log package is used.
+	Logger Logger
+
+	// Verbose turns on progress logging of the runner.
+	Verbose bool
+}
+
+func (c Config) logger() Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return defaultLogger{verbose: c.Verbose}
+}
+
+// Validate checks the Config for missing or inconsistent settings.
+// It returns a list of human readable problems, empty if the Config is valid.
+func (c Config) Validate() (msgs []string) {
+	if c.RPS <= 0 {
+		msgs = append(msgs, "RPS must be larger than zero")
+	}
+	if c.AttackTimeSec <= 0 {
+		msgs = append(msgs, "AttackTimeSec must be larger than zero")
+	}
+	if c.RampupTimeSec > c.AttackTimeSec {
+		msgs = append(msgs, "RampupTimeSec cannot be larger than AttackTimeSec")
+	}
+	return
+}
+
+func (c Config) rampupStrategy() string {
+	if len(c.RampupStrategy) == 0 {
+		return "linear"
+	}
+	return c.RampupStrategy
+}
+
+func (c Config) timeout() time.Duration {
+	if c.DoTimeoutSec <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(c.DoTimeoutSec) * time.Second
+}
@@ -0,0 +1,196 @@
+package hazana
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/rpc"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WorkerAddr is the network address (host:port) of a hazana worker process
+// started with RunWorker.
+type WorkerAddr string
+
+// DistributedPlan describes what RunCoordinator ships to every worker: which
+// Attack, registered through RegisterAttack, to run and the Config to run it
+// with. The Config's RPS is the desired global RPS; it is split evenly
+// across workers unless Weights gives a worker its own share.
+type DistributedPlan struct {
+	Attack  string
+	Config  Config
+	Weights map[WorkerAddr]int
+}
+
+// workerArgs is shipped from the coordinator to a worker over net/rpc.
+type workerArgs struct {
+	Attack  string
+	Config  workerConfig
+	StartAt time.Time
+}
+
+// workerConfig is the plan-relevant subset of a Config that a worker needs
+// to run its share of the attack: RPS, durations and Stages. net/rpc's
+// default gob encoding can't carry Config's Logger, Exporters or
+// OnSLOEvent - an interface or a func - so those never leave the
+// coordinator; a worker always runs with hazana's defaults for them.
+type workerConfig struct {
+	RPS            int
+	AttackTimeSec  int
+	RampupTimeSec  int
+	RampupStrategy string
+	DoTimeoutSec   int
+	Stages         []Stage
+}
+
+// newWorkerConfig extracts the workerConfig subset out of c.
+func newWorkerConfig(c Config) workerConfig {
+	return workerConfig{
+		RPS:            c.RPS,
+		AttackTimeSec:  c.AttackTimeSec,
+		RampupTimeSec:  c.RampupTimeSec,
+		RampupStrategy: c.RampupStrategy,
+		DoTimeoutSec:   c.DoTimeoutSec,
+		Stages:         c.Stages,
+	}
+}
+
+// config expands wc back into a Config, ready to pass to runConfig.
+func (wc workerConfig) config() Config {
+	return Config{
+		RPS:            wc.RPS,
+		AttackTimeSec:  wc.AttackTimeSec,
+		RampupTimeSec:  wc.RampupTimeSec,
+		RampupStrategy: wc.RampupStrategy,
+		DoTimeoutSec:   wc.DoTimeoutSec,
+		Stages:         wc.Stages,
+	}
+}
+
+// workerReply carries one worker's RunReport back to the coordinator.
+type workerReply struct {
+	Report RunReport
+}
+
+// coordinatorService is the net/rpc service a worker exposes to the coordinator.
+type coordinatorService struct{}
+
+// Attack runs args.Config against the Attack registered as args.Attack, first
+// waiting until args.StartAt so every worker's full attack starts in lockstep.
+// It uses runConfig rather than Run so a bad Config fails this one RPC
+// instead of exiting the worker process RunWorker keeps serving.
+func (coordinatorService) Attack(args workerArgs, reply *workerReply) error {
+	factory, ok := lookupAttack(args.Attack)
+	if !ok {
+		return fmt.Errorf("no Attack registered under [%s]", args.Attack)
+	}
+	time.Sleep(time.Until(args.StartAt))
+	report, msg := runConfig(factory(), args.Config.config())
+	if len(msg) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(msg, "; "))
+	}
+	reply.Report = report
+	return nil
+}
+
+// RunWorker starts a hazana worker that listens on listenAddr until a
+// coordinator dispatches an attack to it with RunCoordinator. It blocks,
+// serving every connection it accepts until the listener fails, and returns
+// any error from registering the service or setting up the listener.
+func RunWorker(listenAddr string) error {
+	if err := rpc.Register(coordinatorService{}); err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return err
+	}
+	log.Printf("hazana worker listening on [%s]\n", listenAddr)
+	rpc.Accept(listener)
+	return nil
+}
+
+// CoordinatorReport is the result of a distributed run: every worker's own
+// RunReport plus a Global RunReport with their Metrics merged together,
+// each RequestLabel tagged with the worker that produced it.
+type CoordinatorReport struct {
+	Workers map[WorkerAddr]RunReport
+	Global  RunReport
+}
+
+// RunCoordinator splits plan across workers: it dials each one, ships the
+// attack identifier and a per-worker share of plan.Config.RPS, and issues a
+// synchronized start so every worker's full attack begins at the same instant.
+func RunCoordinator(plan DistributedPlan, workers []WorkerAddr) CoordinatorReport {
+	startAt := time.Now().Add(2 * time.Second) // give every dial+call time to land before the synchronized start
+	report := CoordinatorReport{Workers: map[WorkerAddr]RunReport{}}
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w WorkerAddr) {
+			defer wg.Done()
+			cfg := newWorkerConfig(plan.Config)
+			cfg.RPS = rpsFor(plan, w, len(workers))
+
+			client, err := rpc.Dial("tcp", string(w))
+			if err != nil {
+				log.Printf("[coordinator] failed to dial worker [%s]: %v\n", w, err)
+				return
+			}
+			defer client.Close()
+
+			var reply workerReply
+			args := workerArgs{Attack: plan.Attack, Config: cfg, StartAt: startAt}
+			if err := client.Call("coordinatorService.Attack", args, &reply); err != nil {
+				log.Printf("[coordinator] worker [%s] failed: %v\n", w, err)
+				return
+			}
+			mutex.Lock()
+			report.Workers[w] = reply.Report
+			mutex.Unlock()
+		}(w)
+	}
+	wg.Wait()
+
+	report.Global = mergeReports(report.Workers)
+	return report
+}
+
+// rpsFor returns the worker's share of plan.Config.RPS: its Weight if given,
+// otherwise an even split across n workers. It never returns less than 1, so
+// a plan with more workers than RPS (or a worker missing from Weights in an
+// uneven split) still produces a valid Config rather than failing Validate.
+func rpsFor(plan DistributedPlan, w WorkerAddr, n int) int {
+	rps := plan.Config.RPS / n
+	if weight, ok := plan.Weights[w]; ok {
+		rps = weight
+	}
+	if rps < 1 {
+		rps = 1
+	}
+	return rps
+}
+
+// mergeReports folds every worker's RunReport into one, tagging each
+// RequestLabel with the worker it came from so per-worker contributions
+// stay distinguishable in the merged Metrics.
+func mergeReports(workers map[WorkerAddr]RunReport) RunReport {
+	merged := RunReport{Metrics: map[string]*Metrics{}}
+	for addr, r := range workers {
+		if merged.StartedAt.IsZero() || r.StartedAt.Before(merged.StartedAt) {
+			merged.StartedAt = r.StartedAt
+		}
+		if r.FinishedAt.After(merged.FinishedAt) {
+			merged.FinishedAt = r.FinishedAt
+		}
+		merged.Configuration = r.Configuration
+		for label, m := range r.Metrics {
+			merged.Metrics[fmt.Sprintf("%s worker=%s", label, addr)] = m
+		}
+	}
+	return merged
+}
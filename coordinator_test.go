@@ -0,0 +1,67 @@
+package hazana
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRpsFor(t *testing.T) {
+	t.Run("even split across workers", func(t *testing.T) {
+		plan := DistributedPlan{Config: Config{RPS: 100}}
+		if got := rpsFor(plan, "w1", 4); got != 25 {
+			t.Errorf("rpsFor = %d, want 25", got)
+		}
+	})
+
+	t.Run("Weights overrides the even split", func(t *testing.T) {
+		plan := DistributedPlan{
+			Config:  Config{RPS: 100},
+			Weights: map[WorkerAddr]int{"w1": 90},
+		}
+		if got := rpsFor(plan, "w1", 4); got != 90 {
+			t.Errorf("rpsFor = %d, want 90", got)
+		}
+	})
+
+	t.Run("never returns less than 1", func(t *testing.T) {
+		plan := DistributedPlan{Config: Config{RPS: 1}}
+		if got := rpsFor(plan, "w1", 10); got != 1 {
+			t.Errorf("rpsFor = %d, want 1", got)
+		}
+	})
+}
+
+func TestMergeReports(t *testing.T) {
+	t1 := time.Now()
+	t2 := t1.Add(time.Second)
+	workers := map[WorkerAddr]RunReport{
+		"w1": {
+			StartedAt:  t1,
+			FinishedAt: t2,
+			Metrics:    map[string]*Metrics{"GET /users": {Requests: 10}},
+		},
+		"w2": {
+			StartedAt:  t2,
+			FinishedAt: t2.Add(time.Second),
+			Metrics:    map[string]*Metrics{"GET /users": {Requests: 20}},
+		},
+	}
+
+	merged := mergeReports(workers)
+
+	if !merged.StartedAt.Equal(t1) {
+		t.Errorf("StartedAt = %v, want the earliest worker's %v", merged.StartedAt, t1)
+	}
+	if !merged.FinishedAt.Equal(t2.Add(time.Second)) {
+		t.Errorf("FinishedAt = %v, want the latest worker's %v", merged.FinishedAt, t2.Add(time.Second))
+	}
+	if len(merged.Metrics) != 2 {
+		t.Fatalf("merged.Metrics has %d entries, want 2 (one per worker)", len(merged.Metrics))
+	}
+	if m := merged.Metrics["GET /users worker=w1"]; m == nil || m.Requests != 10 {
+		t.Errorf("merged.Metrics[GET /users worker=w1] = %v, want Requests=10", m)
+	}
+	if m := merged.Metrics["GET /users worker=w2"]; m == nil || m.Requests != 20 {
+		t.Errorf("merged.Metrics[GET /users worker=w2] = %v, want Requests=20", m)
+	}
+}
@@ -0,0 +1,17 @@
+package hazana
+
+// Exporter receives live updates from a running attack, so long soak tests
+// don't stay opaque until reportMetrics produces the final RunReport.
+type Exporter interface {
+	// OnResult is called once for every result collected.
+	OnResult(r Result)
+
+	// OnTick is called periodically with a MetricsSnapshot of the Metrics
+	// accumulated so far, keyed by RequestLabel. A snapshot is handed over
+	// rather than the live *Metrics because those keep being mutated by the
+	// attack's results collector for as long as the run is in progress.
+	OnTick(snapshot map[string]MetricsSnapshot)
+
+	// Close releases any resources the Exporter opened, e.g. a listener.
+	Close()
+}
@@ -0,0 +1,23 @@
+// Package hclog adapts a github.com/hashicorp/go-hclog.Logger to hazana.Logger.
+package hclog
+
+import (
+	gohclog "github.com/hashicorp/go-hclog"
+
+	"github.com/lapd-golang/hazana"
+)
+
+// Adapter wraps an hclog.Logger so it can be used as hazana's Config.Logger.
+type Adapter struct {
+	Log gohclog.Logger
+}
+
+// New returns a hazana.Logger that forwards every call to log.
+func New(log gohclog.Logger) hazana.Logger {
+	return Adapter{Log: log}
+}
+
+func (a Adapter) Debug(msg string, kv ...interface{}) { a.Log.Debug(msg, kv...) }
+func (a Adapter) Info(msg string, kv ...interface{})  { a.Log.Info(msg, kv...) }
+func (a Adapter) Warn(msg string, kv ...interface{})  { a.Log.Warn(msg, kv...) }
+func (a Adapter) Error(msg string, kv ...interface{}) { a.Log.Error(msg, kv...) }
@@ -0,0 +1,33 @@
+package hazana
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// JSONLinesExporter writes one JSON object per line to its io.Writer on
+// every OnTick, so downstream tools like Loki/Grafana can tail a running
+// test's live snapshots.
+type JSONLinesExporter struct {
+	encoder *json.Encoder
+}
+
+// NewJSONLinesExporter returns a JSONLinesExporter that writes its snapshots to w.
+func NewJSONLinesExporter(w io.Writer) *JSONLinesExporter {
+	return &JSONLinesExporter{encoder: json.NewEncoder(w)}
+}
+
+type jsonLinesSnapshot struct {
+	At      time.Time                  `json:"at"`
+	Metrics map[string]MetricsSnapshot `json:"metrics"`
+}
+
+// OnResult is a no-op; JSONLinesExporter only writes the periodic snapshot from OnTick.
+func (e *JSONLinesExporter) OnResult(r Result) {}
+
+func (e *JSONLinesExporter) OnTick(snapshot map[string]MetricsSnapshot) {
+	e.encoder.Encode(jsonLinesSnapshot{At: time.Now(), Metrics: snapshot})
+}
+
+func (e *JSONLinesExporter) Close() {}
@@ -0,0 +1,47 @@
+package hazana
+
+import (
+	"fmt"
+	"log"
+)
+
+// Logger receives leveled, structured events from the runner. kv alternates
+// key, value, key, value, ... following the convention used by structured
+// loggers such as go-hclog and zap, so hazana's adapters for them stay thin.
+type Logger interface {
+	Debug(msg string, kv ...interface{})
+	Info(msg string, kv ...interface{})
+	Warn(msg string, kv ...interface{})
+	Error(msg string, kv ...interface{})
+}
+
+// defaultLogger adapts the stdlib log package to Logger, so Config.Logger
+// can be left unset and hazana behaves as it always has: Debug/Info only
+// print when Verbose is set, Warn/Error always do.
+type defaultLogger struct {
+	verbose bool
+}
+
+func (l defaultLogger) Debug(msg string, kv ...interface{}) {
+	if l.verbose {
+		l.log(msg, kv)
+	}
+}
+
+func (l defaultLogger) Info(msg string, kv ...interface{}) {
+	if l.verbose {
+		l.log(msg, kv)
+	}
+}
+
+func (l defaultLogger) Warn(msg string, kv ...interface{}) { l.log(msg, kv) }
+
+func (l defaultLogger) Error(msg string, kv ...interface{}) { l.log(msg, kv) }
+
+func (defaultLogger) log(msg string, kv []interface{}) {
+	line := msg
+	for i := 0; i+1 < len(kv); i += 2 {
+		line += fmt.Sprintf(" %v=%v", kv[i], kv[i+1])
+	}
+	log.Println(line)
+}
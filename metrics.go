@@ -0,0 +1,100 @@
+package hazana
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Metrics accumulates the results of all the Do calls that share a RequestLabel.
+type Metrics struct {
+	mutex sync.Mutex
+
+	Requests int
+	Errors   int
+
+	samples []time.Duration
+
+	MeanLatency time.Duration
+	P90Latency  time.Duration
+	P99Latency  time.Duration
+	MaxLatency  time.Duration
+}
+
+// add folds one result into the Metrics. It is safe for concurrent use.
+func (m *Metrics) add(s result) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.Requests++
+	if s.doResult.Error != nil {
+		m.Errors++
+	}
+	m.samples = append(m.samples, s.elapsed)
+}
+
+// updateLatencies recomputes the latency percentiles from the collected samples.
+// It is called periodically while the attack runs, for the live export and
+// SLO checks, and once more after the attack has finished.
+func (m *Metrics) updateLatencies() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if len(m.samples) == 0 {
+		return
+	}
+	sort.Slice(m.samples, func(i, j int) bool { return m.samples[i] < m.samples[j] })
+	var total time.Duration
+	for _, each := range m.samples {
+		total += each
+	}
+	m.MeanLatency = total / time.Duration(len(m.samples))
+	m.P90Latency = m.percentile(90)
+	m.P99Latency = m.percentile(99)
+	m.MaxLatency = m.samples[len(m.samples)-1]
+}
+
+func (m *Metrics) percentile(p int) time.Duration {
+	idx := (len(m.samples) * p) / 100
+	if idx >= len(m.samples) {
+		idx = len(m.samples) - 1
+	}
+	return m.samples[idx]
+}
+
+// ErrorRatio returns the fraction of requests that resulted in an error, 0 when none were made.
+func (m *Metrics) ErrorRatio() float64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.Requests == 0 {
+		return 0
+	}
+	return float64(m.Errors) / float64(m.Requests)
+}
+
+// MetricsSnapshot is a lock-free, point-in-time copy of a Metrics' counters
+// and latencies. Exporters receive these instead of a *Metrics, since the
+// *Metrics itself keeps being mutated by the attack's results collector for
+// as long as the run is live.
+type MetricsSnapshot struct {
+	Requests int
+	Errors   int
+
+	MeanLatency time.Duration
+	P90Latency  time.Duration
+	P99Latency  time.Duration
+	MaxLatency  time.Duration
+}
+
+// Snapshot returns a MetricsSnapshot of m's current fields, taking m's
+// mutex so the copy is internally consistent.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return MetricsSnapshot{
+		Requests:    m.Requests,
+		Errors:      m.Errors,
+		MeanLatency: m.MeanLatency,
+		P90Latency:  m.P90Latency,
+		P99Latency:  m.P99Latency,
+		MaxLatency:  m.MaxLatency,
+	}
+}
@@ -0,0 +1,205 @@
+package hazana
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+var (
+	attackFactoriesMutex sync.RWMutex
+	attackFactories      = map[string]func() Attack{}
+)
+
+// RegisterAttack makes an Attack factory available to Plan Scenarios under name.
+// It is typically called from an init function of the package that implements the Attack.
+func RegisterAttack(name string, factory func() Attack) {
+	attackFactoriesMutex.Lock()
+	defer attackFactoriesMutex.Unlock()
+	attackFactories[name] = factory
+}
+
+func lookupAttack(name string) (func() Attack, bool) {
+	attackFactoriesMutex.RLock()
+	defer attackFactoriesMutex.RUnlock()
+	f, ok := attackFactories[name]
+	return f, ok
+}
+
+// Scenario describes one named entry in a Plan: which Attack, registered
+// through RegisterAttack, to run and the Config overrides to apply to it.
+type Scenario struct {
+	Name string `json:"name"`
+
+	// Attack is the name a factory was registered under with RegisterAttack.
+	Attack string `json:"attack"`
+
+	RPS           int `json:"rps,omitempty"`
+	AttackTimeSec int `json:"attackTimeSec,omitempty"`
+	RampupTimeSec int `json:"rampupTimeSec,omitempty"`
+
+	// Weight distributes the Phase's combined RPS across the Scenarios that
+	// share it; it is ignored for a Scenario without a Phase.
+	Weight int `json:"weight,omitempty"`
+
+	// Phase groups Scenarios that must run concurrently. Scenarios without a
+	// Phase run on their own, one after another.
+	Phase string `json:"phase,omitempty"`
+}
+
+// Plan is a JSON-serializable description of one or more Scenarios to run
+// against a shared base Config, so a load test recipe can be checked into
+// source control and composed instead of written as a new main package.
+type Plan struct {
+	// BaseConfig supplies the Config fields every Scenario inherits unless
+	// it overrides them.
+	BaseConfig Config `json:"baseConfig"`
+
+	Scenarios []Scenario `json:"scenarios"`
+
+	// StopOnFirstFailure stops launching the remaining Scenarios as soon as
+	// one of them reports an error. When false, every Scenario always runs.
+	StopOnFirstFailure bool `json:"stopOnFirstFailure"`
+}
+
+// LoadPlan reads and parses a Plan from the JSON file at path.
+func LoadPlan(path string) (Plan, error) {
+	var plan Plan
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return plan, err
+	}
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return plan, fmt.Errorf("failed to parse run plan [%s]: %v", path, err)
+	}
+	return plan, nil
+}
+
+// configFor merges a Scenario's overrides onto the Plan's BaseConfig.
+// weightedRPS, when non-zero, is this Scenario's share of its Phase's
+// combined RPS as computed by distributeRPS, and is used unless the
+// Scenario set its own RPS override.
+func (p Plan) configFor(s Scenario, weightedRPS int) Config {
+	c := p.BaseConfig
+	switch {
+	case s.RPS > 0:
+		c.RPS = s.RPS
+	case weightedRPS > 0:
+		c.RPS = weightedRPS
+	}
+	if s.AttackTimeSec > 0 {
+		c.AttackTimeSec = s.AttackTimeSec
+	}
+	if s.RampupTimeSec > 0 {
+		c.RampupTimeSec = s.RampupTimeSec
+	}
+	return c
+}
+
+// distributeRPS splits totalRPS across phase's Scenarios by Weight, for the
+// Scenarios that share a Phase, didn't set their own RPS override, and did
+// set a Weight. It returns nothing for a phase of a single Scenario, since
+// there's nothing to distribute.
+func distributeRPS(totalRPS int, phase []Scenario) map[string]int {
+	rps := map[string]int{}
+	if len(phase) < 2 {
+		return rps
+	}
+	var totalWeight int
+	for _, s := range phase {
+		if s.RPS == 0 {
+			totalWeight += s.Weight
+		}
+	}
+	if totalWeight == 0 {
+		return rps
+	}
+	for _, s := range phase {
+		if s.RPS == 0 && s.Weight > 0 {
+			rps[s.Name] = totalRPS * s.Weight / totalWeight
+		}
+	}
+	return rps
+}
+
+// groupByPhase returns the Plan's Scenarios grouped in the order phases are
+// first seen; Scenarios without a Phase each get their own single-entry group.
+func groupByPhase(scenarios []Scenario) [][]Scenario {
+	var groups [][]Scenario
+	index := map[string]int{}
+	for _, s := range scenarios {
+		if len(s.Phase) == 0 {
+			groups = append(groups, []Scenario{s})
+			continue
+		}
+		i, ok := index[s.Phase]
+		if !ok {
+			i = len(groups)
+			index[s.Phase] = i
+			groups = append(groups, []Scenario{})
+		}
+		groups[i] = append(groups[i], s)
+	}
+	return groups
+}
+
+// RunPlanReport merges the RunReport of every Scenario in a Plan, keyed by
+// Scenario name.
+type RunPlanReport struct {
+	Scenarios map[string]RunReport
+}
+
+// RunPlan executes every Scenario in plan, grouping the Scenarios that share
+// a Phase so they attack concurrently, and returns a RunPlanReport keyed by
+// Scenario name. Each Scenario runs with the same rampup/full-attack
+// semantics as Run, except that an invalid derived Config is reported as a
+// [run plan error] for that one Scenario instead of exiting the process,
+// since a Phase's other Scenarios are still attacking concurrently.
+//
+// When plan.StopOnFirstFailure is set, RunPlan stops launching further
+// phases as soon as a Scenario in the current phase reports an error;
+// otherwise every Scenario in the Plan always runs.
+func RunPlan(plan Plan) RunPlanReport {
+	report := RunPlanReport{Scenarios: map[string]RunReport{}}
+
+	for _, phase := range groupByPhase(plan.Scenarios) {
+		weightedRPS := distributeRPS(plan.BaseConfig.RPS, phase)
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		failed := false
+		for _, scenario := range phase {
+			factory, ok := lookupAttack(scenario.Attack)
+			if !ok {
+				fmt.Println("[run plan error] no Attack registered under", scenario.Attack)
+				continue
+			}
+			wg.Add(1)
+			go func(scenario Scenario, factory func() Attack) {
+				defer wg.Done()
+				r, msg := runConfig(factory(), plan.configFor(scenario, weightedRPS[scenario.Name]))
+				if len(msg) > 0 {
+					fmt.Printf("[run plan error] invalid config for scenario [%s]: %s\n", scenario.Name, strings.Join(msg, "; "))
+					mutex.Lock()
+					failed = true
+					mutex.Unlock()
+					return
+				}
+				mutex.Lock()
+				defer mutex.Unlock()
+				report.Scenarios[scenario.Name] = r
+				for _, m := range r.Metrics {
+					if m.Errors > 0 {
+						failed = true
+					}
+				}
+			}(scenario, factory)
+		}
+		wg.Wait()
+		if failed && plan.StopOnFirstFailure {
+			break
+		}
+	}
+	return report
+}
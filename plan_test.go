@@ -0,0 +1,82 @@
+package hazana
+
+import "testing"
+
+func TestDistributeRPS(t *testing.T) {
+	t.Run("single scenario phase returns nothing to distribute", func(t *testing.T) {
+		phase := []Scenario{{Name: "a", Weight: 1}}
+		if got := distributeRPS(100, phase); len(got) != 0 {
+			t.Errorf("distributeRPS = %v, want empty", got)
+		}
+	})
+
+	t.Run("splits by weight", func(t *testing.T) {
+		phase := []Scenario{
+			{Name: "a", Weight: 1},
+			{Name: "b", Weight: 3},
+		}
+		got := distributeRPS(100, phase)
+		if got["a"] != 25 || got["b"] != 75 {
+			t.Errorf("distributeRPS = %v, want a=25 b=75", got)
+		}
+	})
+
+	t.Run("integer division truncates, dropping the remainder", func(t *testing.T) {
+		phase := []Scenario{
+			{Name: "a", Weight: 1},
+			{Name: "b", Weight: 1},
+			{Name: "c", Weight: 1},
+		}
+		got := distributeRPS(100, phase)
+		if got["a"] != 33 || got["b"] != 33 || got["c"] != 33 {
+			t.Errorf("distributeRPS = %v, want a=33 b=33 c=33", got)
+		}
+	})
+
+	t.Run("a Scenario with its own RPS override is excluded from the split", func(t *testing.T) {
+		phase := []Scenario{
+			{Name: "a", RPS: 10, Weight: 1},
+			{Name: "b", Weight: 1},
+		}
+		got := distributeRPS(100, phase)
+		if _, ok := got["a"]; ok {
+			t.Errorf("distributeRPS = %v, want no entry for a", got)
+		}
+		if got["b"] != 100 {
+			t.Errorf("distributeRPS = %v, want b=100", got)
+		}
+	})
+
+	t.Run("zero total weight returns nothing to distribute", func(t *testing.T) {
+		phase := []Scenario{{Name: "a"}, {Name: "b"}}
+		if got := distributeRPS(100, phase); len(got) != 0 {
+			t.Errorf("distributeRPS = %v, want empty", got)
+		}
+	})
+}
+
+func TestGroupByPhase(t *testing.T) {
+	scenarios := []Scenario{
+		{Name: "solo-1"},
+		{Name: "a", Phase: "p1"},
+		{Name: "b", Phase: "p1"},
+		{Name: "solo-2"},
+		{Name: "c", Phase: "p2"},
+	}
+	groups := groupByPhase(scenarios)
+	if len(groups) != 4 {
+		t.Fatalf("groupByPhase returned %d groups, want 4", len(groups))
+	}
+	if len(groups[0]) != 1 || groups[0][0].Name != "solo-1" {
+		t.Errorf("groups[0] = %v, want [solo-1]", groups[0])
+	}
+	if len(groups[1]) != 2 || groups[1][0].Name != "a" || groups[1][1].Name != "b" {
+		t.Errorf("groups[1] = %v, want [a b]", groups[1])
+	}
+	if len(groups[2]) != 1 || groups[2][0].Name != "solo-2" {
+		t.Errorf("groups[2] = %v, want [solo-2]", groups[2])
+	}
+	if len(groups[3]) != 1 || groups[3][0].Name != "c" {
+		t.Errorf("groups[3] = %v, want [c]", groups[3])
+	}
+}
@@ -0,0 +1,96 @@
+package hazana
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// PrometheusExporter serves the live RPS, latency percentiles and error
+// counts per RequestLabel in the Prometheus text format on /metrics: request
+// and error totals as counters, RPS and latency percentiles as gauges.
+type PrometheusExporter struct {
+	mutex sync.RWMutex
+
+	snapshot map[string]MetricsSnapshot
+	rps      map[string]float64
+
+	prevRequests map[string]int
+	prevAt       time.Time
+
+	server *http.Server
+}
+
+// NewPrometheusExporter starts serving /metrics on addr, e.g. ":9090", and
+// returns the Exporter to pass through Config.Exporters.
+func NewPrometheusExporter(addr string) *PrometheusExporter {
+	e := &PrometheusExporter{snapshot: map[string]MetricsSnapshot{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.handleMetrics)
+	e.server = &http.Server{Addr: addr, Handler: mux}
+	go e.server.ListenAndServe()
+	return e
+}
+
+// OnResult is a no-op; PrometheusExporter only exposes the periodic snapshot from OnTick.
+func (e *PrometheusExporter) OnResult(r Result) {}
+
+// OnTick updates the served snapshot and derives the per-RequestLabel RPS
+// gauge from the change in Metrics.Requests since the previous tick.
+func (e *PrometheusExporter) OnTick(snapshot map[string]MetricsSnapshot) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	now := time.Now()
+	rps := map[string]float64{}
+	if elapsed := now.Sub(e.prevAt).Seconds(); !e.prevAt.IsZero() && elapsed > 0 {
+		for label, m := range snapshot {
+			rps[label] = float64(m.Requests-e.prevRequests[label]) / elapsed
+		}
+	}
+
+	prevRequests := make(map[string]int, len(snapshot))
+	for label, m := range snapshot {
+		prevRequests[label] = m.Requests
+	}
+
+	e.snapshot = snapshot
+	e.rps = rps
+	e.prevRequests = prevRequests
+	e.prevAt = now
+}
+
+func (e *PrometheusExporter) Close() {
+	e.server.Close()
+}
+
+func (e *PrometheusExporter) handleMetrics(w http.ResponseWriter, req *http.Request) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	fmt.Fprintln(w, "# HELP hazana_requests_total Number of requests made, per RequestLabel.")
+	fmt.Fprintln(w, "# TYPE hazana_requests_total counter")
+	for label, m := range e.snapshot {
+		fmt.Fprintf(w, "hazana_requests_total{request_label=%q} %d\n", label, m.Requests)
+	}
+
+	fmt.Fprintln(w, "# HELP hazana_rps Requests per second over the previous export tick, per RequestLabel.")
+	fmt.Fprintln(w, "# TYPE hazana_rps gauge")
+	for label, rps := range e.rps {
+		fmt.Fprintf(w, "hazana_rps{request_label=%q} %f\n", label, rps)
+	}
+
+	fmt.Fprintln(w, "# HELP hazana_errors_total Number of failed requests, per RequestLabel.")
+	fmt.Fprintln(w, "# TYPE hazana_errors_total counter")
+	for label, m := range e.snapshot {
+		fmt.Fprintf(w, "hazana_errors_total{request_label=%q} %d\n", label, m.Errors)
+	}
+
+	fmt.Fprintln(w, "# HELP hazana_latency_seconds Latency percentiles, per RequestLabel.")
+	fmt.Fprintln(w, "# TYPE hazana_latency_seconds gauge")
+	for label, m := range e.snapshot {
+		fmt.Fprintf(w, "hazana_latency_seconds{request_label=%q,quantile=\"0.9\"} %f\n", label, m.P90Latency.Seconds())
+		fmt.Fprintf(w, "hazana_latency_seconds{request_label=%q,quantile=\"0.99\"} %f\n", label, m.P99Latency.Seconds())
+	}
+}
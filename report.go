@@ -0,0 +1,16 @@
+package hazana
+
+import "time"
+
+// RunReport is the result of a complete Run, the attack Config used and the
+// Metrics collected for every RequestLabel that was seen.
+type RunReport struct {
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	Configuration Config
+	Metrics       map[string]*Metrics
+
+	// AbortReason is set when an SLO breach made the runner stop the full
+	// attack early; it is empty for a run that completed normally.
+	AbortReason string
+}
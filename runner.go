@@ -3,8 +3,9 @@ package hazana
 import (
 	"flag"
 	"fmt"
-	"log"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/ratelimit"
@@ -16,28 +17,33 @@ type runner struct {
 	next, quit      chan bool
 	results         chan result
 	prototype       Attack
+	metricsMutex    sync.RWMutex
 	metrics         map[string]*Metrics
 	resultsPipeline func(r result) result
+
+	startedAt   time.Time
+	abort       chan struct{}
+	abortReason string
+	rpsScale    int32 // atomic percentage applied to the configured RPS, 100 = no throttle
 }
 
 // Run starts attacking a service using an Attack implementation and a configuration.
 // Return a report with statistics per sample and the configuration used.
 func Run(a Attack, c Config) RunReport {
-	if c.Verbose {
-		log.Println("hazana - load runner")
-	}
-	r := new(runner)
-	r.config = c
-	r.prototype = a
+	c.logger().Info("hazana.start")
 
 	// do a test if the flag says so
 	if *oSample > 0 {
+		r := new(runner)
+		r.config = c
+		r.prototype = a
 		r.test(*oSample)
 		os.Exit(0)
 		// unreachable
 		return RunReport{}
 	}
-	if msg := c.Validate(); len(msg) > 0 {
+	report, msg := runConfig(a, c)
+	if len(msg) > 0 {
 		for _, each := range msg {
 			fmt.Println("[config error]", each)
 		}
@@ -45,8 +51,22 @@ func Run(a Attack, c Config) RunReport {
 		flag.Usage()
 		os.Exit(0)
 	}
+	return report
+}
+
+// runConfig runs a against c like Run, but returns Config.Validate's messages
+// instead of exiting the process, so a long-lived caller such as RunWorker
+// can report a bad Config to whoever sent it without taking down every other
+// attack it might ever serve.
+func runConfig(a Attack, c Config) (RunReport, []string) {
+	if msg := c.Validate(); len(msg) > 0 {
+		return RunReport{}, msg
+	}
+	r := new(runner)
+	r.config = c
+	r.prototype = a
 	r.init()
-	return r.run()
+	return r.run(), nil
 }
 
 func (r *runner) init() {
@@ -56,15 +76,16 @@ func (r *runner) init() {
 	r.attackers = []Attack{}
 	r.metrics = map[string]*Metrics{}
 	r.resultsPipeline = r.addResult
+	r.abort = make(chan struct{})
+	atomic.StoreInt32(&r.rpsScale, 100)
 }
 
 func (r *runner) spawnAttacker() {
-	if r.config.Verbose {
-		log.Printf("setup and spawn new attacker [%d]\n", len(r.attackers)+1)
-	}
+	attackerID := len(r.attackers) + 1
+	r.config.logger().Debug("attacker.spawning", "attacker_id", attackerID)
 	attacker := r.prototype.Clone()
 	if err := attacker.Setup(r.config); err != nil {
-		log.Printf("attacker [%d] setup failed with [%v]\n", len(r.attackers)+1, err)
+		r.config.logger().Error("attacker.setup_failed", "attacker_id", attackerID, "error", err)
 		return
 	}
 	r.attackers = append(r.attackers, attacker)
@@ -73,11 +94,13 @@ func (r *runner) spawnAttacker() {
 
 // addResult is called from a dedicated goroutine.
 func (r *runner) addResult(s result) result {
+	r.metricsMutex.Lock()
 	m, ok := r.metrics[s.doResult.RequestLabel]
 	if !ok {
 		m = new(Metrics)
 		r.metrics[s.doResult.RequestLabel] = m
 	}
+	r.metricsMutex.Unlock()
 	m.add(s)
 	return s
 }
@@ -87,48 +110,251 @@ func (r *runner) addResult(s result) result {
 func (r *runner) test(count int) {
 	probe := r.prototype.Clone()
 	if err := probe.Setup(r.config); err != nil {
-		log.Printf("Test attack setup failed [%v]", err)
+		r.config.logger().Error("attack.test_setup_failed", "error", err)
 		return
 	}
 	defer probe.Teardown()
 	for s := count; s > 0; s-- {
 		now := time.Now()
 		result := probe.Do()
-		log.Printf("Test attack call [%s] took [%v] with status [%v] and error [%v]\n", result.RequestLabel, time.Now().Sub(now), result.StatusCode, result.Error)
+		r.config.logger().Info("attack.test_call", "request_label", result.RequestLabel, "elapsed", time.Now().Sub(now), "status", result.StatusCode, "error", result.Error)
 	}
 }
 
 // run offers the complete flow of a load test.
 func (r *runner) run() RunReport {
 	go r.collectResults()
+	stopExporting := r.startExportTicker()
+	stopSLOMonitor := r.startSLOMonitor()
 	r.rampUp()
 	r.fullAttack()
 	r.quitAttackers()
 	r.tearDownAttackers()
+	close(stopExporting)
+	close(stopSLOMonitor)
+	for _, e := range r.config.Exporters {
+		e.Close()
+	}
 	return r.reportMetrics()
 }
 
+// exportTickInterval is how often OnTick fires on the configured Exporters.
+const exportTickInterval = time.Second
+
+// startExportTicker periodically snapshots r.metrics to every Exporter until
+// the returned channel is closed.
+func (r *runner) startExportTicker() chan struct{} {
+	stop := make(chan struct{})
+	if len(r.config.Exporters) == 0 {
+		return stop
+	}
+	go func() {
+		ticker := time.NewTicker(exportTickInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.exportTick()
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return stop
+}
+
+func (r *runner) exportTick() {
+	snapshot := map[string]MetricsSnapshot{}
+	r.metricsMutex.RLock()
+	for label, m := range r.metrics {
+		m.updateLatencies()
+		snapshot[label] = m.Snapshot()
+	}
+	r.metricsMutex.RUnlock()
+	for _, e := range r.config.Exporters {
+		e.OnTick(snapshot)
+	}
+}
+
+// startSLOMonitor periodically evaluates r.config.SLO against the live
+// Metrics until the returned channel is closed. A breach either aborts the
+// full attack (closing r.abort) or halves r.rpsScale, restoring it to 100
+// once the SLO recovers.
+func (r *runner) startSLOMonitor() chan struct{} {
+	stop := make(chan struct{})
+	if r.config.SLO == nil {
+		return stop
+	}
+	go func() {
+		ticker := time.NewTicker(r.config.SLO.evaluationWindow())
+		defer ticker.Stop()
+		throttled := false
+		for {
+			select {
+			case <-ticker.C:
+				breached, errRatio, p99 := r.evaluateSLO()
+				if breached {
+					r.config.logger().Warn("slo.breached", "error_ratio", errRatio, "p99", p99, "action", r.config.SLO.Action)
+					if r.config.OnSLOEvent != nil && !throttled {
+						r.config.OnSLOEvent(SLOEvent{Breached: true, Action: r.config.SLO.Action, ErrorRatio: errRatio, P99Latency: p99})
+					}
+					switch r.config.SLO.Action {
+					case Abort:
+						r.abortReason = fmt.Sprintf("SLO breached: error ratio %.3f, p99 %v", errRatio, p99)
+						close(r.abort)
+						return
+					case Throttle:
+						if !throttled {
+							atomic.StoreInt32(&r.rpsScale, 50)
+							throttled = true
+						}
+					}
+				} else if throttled {
+					atomic.StoreInt32(&r.rpsScale, 100)
+					throttled = false
+					if r.config.OnSLOEvent != nil {
+						r.config.OnSLOEvent(SLOEvent{Breached: false, Action: r.config.SLO.Action, ErrorRatio: errRatio, P99Latency: p99})
+					}
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return stop
+}
+
+// evaluateSLO folds every RequestLabel's Metrics together and checks them
+// against r.config.SLO.
+func (r *runner) evaluateSLO() (breached bool, errRatio float64, p99 time.Duration) {
+	var requests, errors int
+	r.metricsMutex.RLock()
+	for _, m := range r.metrics {
+		m.updateLatencies()
+		requests += m.Requests
+		errors += m.Errors
+		if m.P99Latency > p99 {
+			p99 = m.P99Latency
+		}
+	}
+	r.metricsMutex.RUnlock()
+	if requests < r.config.SLO.MinSamples {
+		return false, 0, p99
+	}
+	errRatio = float64(errors) / float64(requests)
+	if r.config.SLO.MaxErrorRatio > 0 && errRatio > r.config.SLO.MaxErrorRatio {
+		breached = true
+	}
+	if r.config.SLO.MaxP99Latency > 0 && p99 > r.config.SLO.MaxP99Latency {
+		breached = true
+	}
+	return
+}
+
+// stageTick is how often the rate limiter is rebuilt while ramping within a
+// Stage, so the target RPS can be approximated smoothly instead of jumping.
+const stageTick = 250 * time.Millisecond
+
 func (r *runner) fullAttack() {
-	if r.config.Verbose {
-		log.Printf("begin full attack of [%d] remaining seconds\n", r.config.AttackTimeSec-r.config.RampupTimeSec)
+	r.config.logger().Info("attack.begin", "seconds", r.config.AttackTimeSec-r.config.RampupTimeSec)
+	r.startedAt = time.Now()
+	if len(r.config.Stages) == 0 {
+		r.runAtConstantRPS(r.config.RPS, time.Duration(r.config.AttackTimeSec-r.config.RampupTimeSec)*time.Second)
+	} else {
+		r.runStages()
 	}
-	fullAttackStartedAt = time.Now()
-	limiter := ratelimit.New(r.config.RPS) // per second
-	doneDeadline := time.Now().Add(time.Duration(r.config.AttackTimeSec-r.config.RampupTimeSec) * time.Second)
-	for time.Now().Before(doneDeadline) {
+	r.config.logger().Info("attack.end")
+}
+
+// runAtConstantRPS sends next ticks at rps, scaled by r.rpsScale, for the
+// duration d. The limiter is kept for the whole duration and only rebuilt
+// when the scaled RPS actually changes - i.e. when the SLO monitor throttles
+// or recovers - so the common case (no throttle) doesn't pay a rebuilt
+// limiter's reset slack on every tick. It returns early if r.abort fires.
+func (r *runner) runAtConstantRPS(rps int, d time.Duration) {
+	deadline := time.Now().Add(d)
+	currentRPS := r.scaledRPS(rps)
+	limiter := ratelimit.New(currentRPS)
+	for time.Now().Before(deadline) {
+		select {
+		case <-r.abort:
+			return
+		default:
+		}
+		if scaled := r.scaledRPS(rps); scaled != currentRPS {
+			limiter = ratelimit.New(scaled)
+			currentRPS = scaled
+		}
 		limiter.Take()
 		r.next <- true
 	}
-	if r.config.Verbose {
-		log.Printf("end full attack")
+}
+
+// scaledRPS applies r.rpsScale (a throttle percentage maintained by the SLO
+// monitor) to rps.
+func (r *runner) scaledRPS(rps int) int {
+	scaled := rps * int(atomic.LoadInt32(&r.rpsScale)) / 100
+	if scaled <= 0 {
+		scaled = 1
+	}
+	return scaled
+}
+
+// runStages walks r.config.Stages in order, rebuilding the rate limiter at
+// every stage boundary and, within a ramping stage, on every stageTick so
+// the RPS can be interpolated between stages instead of jumping.
+func (r *runner) runStages() {
+	fromRPS := r.config.RPS
+	for i, stage := range r.config.Stages {
+		select {
+		case <-r.abort:
+			return
+		default:
+		}
+		r.config.logger().Info("attack.stage.begin", "stage", i+1, "of", len(r.config.Stages), "seconds", stage.DurationSec, "target_rps", stage.TargetRPS)
+		r.runStage(fromRPS, stage)
+		fromRPS = stage.TargetRPS
+	}
+}
+
+func (r *runner) runStage(fromRPS int, stage Stage) {
+	began := time.Now()
+	total := time.Duration(stage.DurationSec) * time.Second
+	deadline := began.Add(total)
+	for time.Now().Before(deadline) {
+		select {
+		case <-r.abort:
+			return
+		default:
+		}
+		progress := float64(time.Now().Sub(began)) / float64(total)
+		rps := rampedRPS(fromRPS, stage, progress)
+		tickDeadline := time.Now().Add(stageTick)
+		if tickDeadline.After(deadline) {
+			tickDeadline = deadline
+		}
+		r.runAtConstantRPS(rps, tickDeadline.Sub(time.Now()))
 	}
 }
 
+// rampedRPS is the target RPS at progress (0 at the Stage's start, 1 at its
+// end): stage.TargetRPS held constant if stage.HoldRPS is set, otherwise
+// linearly interpolated from fromRPS. It never returns less than 1, so a
+// ramp down towards zero can't stall the attack entirely.
+func rampedRPS(fromRPS int, stage Stage, progress float64) int {
+	rps := stage.TargetRPS
+	if !stage.HoldRPS {
+		rps = fromRPS + int(float64(stage.TargetRPS-fromRPS)*progress)
+	}
+	if rps <= 0 {
+		rps = 1
+	}
+	return rps
+}
+
 func (r *runner) rampUp() {
 	strategy := r.config.rampupStrategy()
-	if r.config.Verbose {
-		log.Printf("begin rampup of [%d] seconds using strategy [%s]\n", r.config.RampupTimeSec, strategy)
-	}
+	r.config.logger().Info("rampup.begin", "strategy", strategy, "seconds", r.config.RampupTimeSec)
 	switch strategy {
 	case "linear":
 		linearIncreasingGoroutinesAndRequestsPerSecondStrategy{}.execute(r)
@@ -137,45 +363,45 @@ func (r *runner) rampUp() {
 	}
 	// restore pipeline function incase it was changed by the rampup strategy
 	r.resultsPipeline = r.addResult
-	if r.config.Verbose {
-		log.Printf("end rampup ending up with [%d] attackers\n", len(r.attackers))
-	}
+	r.config.logger().Info("rampup.end", "attackers", len(r.attackers))
 }
 
 func (r *runner) quitAttackers() {
-	if r.config.Verbose {
-		log.Printf("stopping attackers [%d]\n", len(r.attackers))
-	}
+	r.config.logger().Info("attackers.stopping", "count", len(r.attackers))
 	for range r.attackers {
 		r.quit <- true
 	}
 }
 
 func (r *runner) tearDownAttackers() {
-	if r.config.Verbose {
-		log.Printf("tearing down attackers [%d]\n", len(r.attackers))
-	}
+	r.config.logger().Info("attackers.tearing_down", "count", len(r.attackers))
 	for i, each := range r.attackers {
 		if err := each.Teardown(); err != nil {
-			log.Printf("ERROR failed to teardown attacker [%d]:%v\n", i, err)
+			r.config.logger().Error("attacker.teardown_failed", "attacker_id", i, "error", err)
 		}
 	}
 }
 
 func (r *runner) reportMetrics() RunReport {
+	r.metricsMutex.RLock()
+	defer r.metricsMutex.RUnlock()
 	for _, each := range r.metrics {
 		each.updateLatencies()
 	}
 	return RunReport{
-		StartedAt:     fullAttackStartedAt,
+		StartedAt:     r.startedAt,
 		FinishedAt:    time.Now(),
 		Configuration: r.config,
 		Metrics:       r.metrics,
+		AbortReason:   r.abortReason,
 	}
 }
 
 func (r *runner) collectResults() {
 	for {
-		r.resultsPipeline(<-r.results)
+		s := r.resultsPipeline(<-r.results)
+		for _, e := range r.config.Exporters {
+			e.OnResult(s.doResult)
+		}
 	}
 }
@@ -0,0 +1,55 @@
+package hazana
+
+import "testing"
+
+func TestRampedRPS(t *testing.T) {
+	cases := []struct {
+		name     string
+		fromRPS  int
+		stage    Stage
+		progress float64
+		want     int
+	}{
+		{"held constant ignores fromRPS", 10, Stage{TargetRPS: 100, HoldRPS: true}, 0.5, 100},
+		{"ramp start is fromRPS", 10, Stage{TargetRPS: 110}, 0, 10},
+		{"ramp end is TargetRPS", 10, Stage{TargetRPS: 110}, 1, 110},
+		{"ramp midpoint interpolates linearly", 10, Stage{TargetRPS: 110}, 0.5, 60},
+		{"descending ramp floors at 1", 100, Stage{TargetRPS: 0}, 1, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := rampedRPS(c.fromRPS, c.stage, c.progress); got != c.want {
+				t.Errorf("rampedRPS(%d, %+v, %v) = %d, want %d", c.fromRPS, c.stage, c.progress, got, c.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateSLO(t *testing.T) {
+	r := new(runner)
+	r.config = Config{SLO: &SLO{MaxErrorRatio: 0.1, MinSamples: 10}}
+	r.metrics = map[string]*Metrics{
+		"GET /users": {Requests: 20, Errors: 5},
+	}
+
+	breached, errRatio, _ := r.evaluateSLO()
+	if !breached {
+		t.Fatalf("expected SLO to be breached at error ratio %v", errRatio)
+	}
+	if errRatio != 0.25 {
+		t.Errorf("errRatio = %v, want 0.25", errRatio)
+	}
+}
+
+func TestEvaluateSLOBelowMinSamples(t *testing.T) {
+	r := new(runner)
+	r.config = Config{SLO: &SLO{MaxErrorRatio: 0.1, MinSamples: 100}}
+	r.metrics = map[string]*Metrics{
+		"GET /users": {Requests: 20, Errors: 20},
+	}
+
+	breached, _, _ := r.evaluateSLO()
+	if breached {
+		t.Fatal("expected no SLO evaluation below MinSamples")
+	}
+}
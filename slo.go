@@ -0,0 +1,53 @@
+package hazana
+
+import "time"
+
+// SLOAction decides what the runner does when an SLO is breached.
+type SLOAction int
+
+const (
+	// Abort cancels the remainder of the full attack and records a
+	// RunReport.AbortReason.
+	Abort SLOAction = iota
+	// Throttle halves the current RPS until the SLO recovers.
+	Throttle
+)
+
+// SLO defines the error/latency budget a running attack must stay within.
+// It is evaluated periodically against the live Metrics, every EvaluationWindow.
+type SLO struct {
+	// MaxErrorRatio is the highest acceptable fraction of failed requests.
+	// Zero disables the error ratio check.
+	MaxErrorRatio float64
+
+	// MaxP99Latency is the highest acceptable p99 latency across all
+	// RequestLabels. Zero disables the latency check.
+	MaxP99Latency time.Duration
+
+	// EvaluationWindow is how often the SLO is checked. It defaults to one second.
+	EvaluationWindow time.Duration
+
+	// MinSamples is the minimum number of requests, across all
+	// RequestLabels, before the SLO is evaluated at all.
+	MinSamples int
+
+	// Action decides what happens when the SLO is breached.
+	Action SLOAction
+}
+
+func (s SLO) evaluationWindow() time.Duration {
+	if s.EvaluationWindow <= 0 {
+		return exportTickInterval
+	}
+	return s.EvaluationWindow
+}
+
+// SLOEvent describes one SLO evaluation, delivered to Config.OnSLOEvent
+// whenever the breach state changes: a breach is detected, or a throttled
+// attack recovers.
+type SLOEvent struct {
+	Breached   bool
+	Action     SLOAction
+	ErrorRatio float64
+	P99Latency time.Duration
+}
@@ -0,0 +1,14 @@
+package hazana
+
+// Stage is one segment of a staged RPS profile: hold, or linearly ramp
+// towards, TargetRPS for DurationSec seconds before moving on to the next
+// Stage.
+type Stage struct {
+	DurationSec int
+	TargetRPS   int
+
+	// HoldRPS keeps the RPS constant at TargetRPS for the whole Stage. When
+	// false, RPS ramps linearly from the previous Stage's TargetRPS (or
+	// Config.RPS for the first Stage) up to TargetRPS.
+	HoldRPS bool
+}
@@ -0,0 +1,46 @@
+package hazana
+
+import "time"
+
+// rampupStrategy spawns attackers during the rampup period of a run.
+type rampupStrategy interface {
+	execute(r *runner)
+}
+
+// linearIncreasingGoroutinesAndRequestsPerSecondStrategy spawns one new
+// attacker per second, linearly increasing RPS from zero to the configured
+// Config.RPS over Config.RampupTimeSec seconds.
+type linearIncreasingGoroutinesAndRequestsPerSecondStrategy struct{}
+
+func (s linearIncreasingGoroutinesAndRequestsPerSecondStrategy) execute(r *runner) {
+	if r.config.RampupTimeSec == 0 {
+		return
+	}
+	for step := 1; step <= r.config.RampupTimeSec; step++ {
+		r.spawnAttacker()
+		r.runAtConstantRPS(step*r.config.RPS/r.config.RampupTimeSec, time.Second)
+	}
+}
+
+// spawnAsWeNeedStrategy spawns an attacker only when the existing ones can't
+// keep up with the requested tick, doubling the attacker count each time.
+type spawnAsWeNeedStrategy struct{}
+
+func (s spawnAsWeNeedStrategy) execute(r *runner) {
+	r.spawnAttacker()
+	deadline := time.Now().Add(time.Duration(r.config.RampupTimeSec) * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case r.next <- true:
+		case <-time.After(10 * time.Millisecond):
+			r.config.logger().Debug("rampup.falling_behind", "attackers", len(r.attackers))
+			n := len(r.attackers)
+			if n == 0 {
+				n = 1
+			}
+			for ; n > 0; n-- {
+				r.spawnAttacker()
+			}
+		}
+	}
+}
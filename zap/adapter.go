@@ -0,0 +1,23 @@
+// Package zap adapts a go.uber.org/zap.SugaredLogger to hazana.Logger.
+package zap
+
+import (
+	gozap "go.uber.org/zap"
+
+	"github.com/lapd-golang/hazana"
+)
+
+// Adapter wraps a zap.SugaredLogger so it can be used as hazana's Config.Logger.
+type Adapter struct {
+	Log *gozap.SugaredLogger
+}
+
+// New returns a hazana.Logger that forwards every call to log.
+func New(log *gozap.SugaredLogger) hazana.Logger {
+	return Adapter{Log: log}
+}
+
+func (a Adapter) Debug(msg string, kv ...interface{}) { a.Log.Debugw(msg, kv...) }
+func (a Adapter) Info(msg string, kv ...interface{})  { a.Log.Infow(msg, kv...) }
+func (a Adapter) Warn(msg string, kv ...interface{})  { a.Log.Warnw(msg, kv...) }
+func (a Adapter) Error(msg string, kv ...interface{}) { a.Log.Errorw(msg, kv...) }